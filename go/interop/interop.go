@@ -0,0 +1,112 @@
+// Copyright 2015 Tamás Gulácsi. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package interop bridges Open Location Codes with other spatial
+// indexing schemes: geohashes and S2 cell IDs.
+//
+// Conversion always goes through a cell's center: OLC->other decodes
+// the OLC cell and re-encodes its center in the target scheme, and
+// other->OLC decodes the source cell's center and encodes it as an
+// OLC code. Because the grids don't align, a single OLC code may not
+// fully contain a source cell (or vice versa); use CoverGeohash and
+// CoverS2 to get every OLC cell that overlaps a source cell.
+package interop
+
+import (
+	"errors"
+	"math"
+
+	"github.com/golang/geo/s2"
+	"github.com/mmcloughlin/geohash"
+	"github.com/tgulacsi/go/olc"
+)
+
+// FromGeohash converts a geohash string to an OLC code of length
+// codeLen, using the geohash's center point.
+func FromGeohash(gh string, codeLen int) (string, error) {
+	lat, lng := geohash.Decode(gh)
+	return olc.Encode(lat, lng, codeLen), nil
+}
+
+// ToGeohash converts an OLC code to a geohash of the given precision
+// (number of base-32 characters), using the OLC cell's center point.
+func ToGeohash(code string, precision int) (string, error) {
+	area, err := olc.Decode(code)
+	if err != nil {
+		return "", err
+	}
+	lat, lng := center(area)
+	return geohash.EncodeWithPrecision(lat, lng, uint(precision)), nil
+}
+
+// FromS2CellID converts an S2 cell ID to an OLC code of length codeLen,
+// using the S2 cell's center point.
+func FromS2CellID(id uint64, codeLen int) (string, error) {
+	cellID := s2.CellID(id)
+	if !cellID.IsValid() {
+		return "", errors.New("interop: invalid S2 cell id")
+	}
+	ll := cellID.LatLng()
+	return olc.Encode(ll.Lat.Degrees(), ll.Lng.Degrees(), codeLen), nil
+}
+
+// ToS2CellID converts an OLC code to an S2 cell ID at the given level,
+// using the OLC cell's center point.
+func ToS2CellID(code string, level int) (uint64, error) {
+	area, err := olc.Decode(code)
+	if err != nil {
+		return 0, err
+	}
+	lat, lng := center(area)
+	cellID := s2.CellIDFromLatLng(s2.LatLngFromDegrees(lat, lng)).Parent(level)
+	return uint64(cellID), nil
+}
+
+// CoverGeohash returns every OLC code of length codeLen whose cell
+// intersects gh's bounding box. An invalid codeLen yields a nil slice.
+// gh is not validated, here or in FromGeohash, whose error is always
+// nil.
+func CoverGeohash(gh string, codeLen int) []string {
+	box := geohash.BoundingBox(gh)
+	codes, err := olc.CellsIn(box.MinLat, box.MinLng, box.MaxLat, box.MaxLng, codeLen)
+	if err != nil {
+		return nil
+	}
+	return codes
+}
+
+// CoverS2 returns every OLC code of length codeLen whose cell
+// intersects the S2 cell's bounding box. An invalid id or codeLen
+// yields a nil slice; FromS2CellID reports an invalid id as an error,
+// but not an invalid codeLen.
+func CoverS2(id uint64, codeLen int) []string {
+	cellID := s2.CellID(id)
+	if !cellID.IsValid() {
+		return nil
+	}
+	rect := s2.CellFromCellID(cellID).RectBound()
+	codes, err := olc.CellsIn(
+		rect.Lat.Lo*180/math.Pi, rect.Lng.Lo*180/math.Pi,
+		rect.Lat.Hi*180/math.Pi, rect.Lng.Hi*180/math.Pi,
+		codeLen)
+	if err != nil {
+		return nil
+	}
+	return codes
+}
+
+// center returns the midpoint of an OLC area.
+func center(area olc.Area) (lat, lng float64) {
+	return (area.LatLo + area.LatHi) / 2, (area.LngLo + area.LngHi) / 2
+}
@@ -0,0 +1,158 @@
+// Copyright 2015 Tamás Gulácsi. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package olc
+
+import (
+	"context"
+	"errors"
+)
+
+// Neighbors returns the 8 codes of the same length as code that are
+// adjacent to it, in compass order starting at north and going
+// clockwise: N, NE, E, SE, S, SW, W, NW.
+//
+// Latitude is clamped at ±90°, so the north/south neighbors of a polar
+// cell are themselves. Longitude wraps around at ±180°.
+func Neighbors(code string) ([8]string, error) {
+	var codes [8]string
+	area, err := Decode(code)
+	if err != nil {
+		return codes, err
+	}
+	latSize := area.LatHi - area.LatLo
+	lngSize := area.LngHi - area.LngLo
+	latCenter := (area.LatLo + area.LatHi) / 2
+	lngCenter := (area.LngLo + area.LngHi) / 2
+	n := len(stripCode(code))
+
+	offsets := [8]struct{ dLat, dLng float64 }{
+		{latSize, 0},         // N
+		{latSize, lngSize},   // NE
+		{0, lngSize},         // E
+		{-latSize, lngSize},  // SE
+		{-latSize, 0},        // S
+		{-latSize, -lngSize}, // SW
+		{0, -lngSize},        // W
+		{latSize, -lngSize},  // NW
+	}
+	for i, o := range offsets {
+		lat := clampLatitude(latCenter + o.dLat)
+		lng := normalizeLongitude(lngCenter + o.dLng)
+		codes[i] = Encode(lat, lng, n)
+	}
+	return codes, nil
+}
+
+// CellsIn enumerates every OLC code of length codeLen whose cell
+// overlaps the half-open rectangle [latLo,latHi) x [lngLo,lngHi); a
+// cell that only touches the rectangle's edge is not included. lngLo
+// may be greater than lngHi to describe a rectangle crossing the ±180°
+// meridian.
+func CellsIn(latLo, lngLo, latHi, lngHi float64, codeLen int) ([]string, error) {
+	ch := make(chan string)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(ch)
+		errCh <- CellsInStream(context.Background(), latLo, lngLo, latHi, lngHi, codeLen, ch)
+	}()
+	var codes []string
+	for code := range ch {
+		codes = append(codes, code)
+	}
+	if err := <-errCh; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// CellsInStream is the streaming variant of CellsIn: it sends every
+// matching code to out instead of materializing a slice, for callers
+// that enumerate bounding boxes too large to hold in memory at once.
+// CellsInStream never closes out; the caller owns it when driving
+// CellsInStream directly (CellsIn closes its own internal channel). A caller that stops reading
+// from out before CellsInStream returns must cancel ctx, or the
+// producer blocks forever on the send.
+func CellsInStream(ctx context.Context, latLo, lngLo, latHi, lngHi float64, codeLen int, out chan<- string) error {
+	if out == nil {
+		return errors.New("olc: nil output channel")
+	}
+	if lngHi < lngLo {
+		lngHi += 360
+	}
+
+	latSize, lngSize, err := cellSize(codeLen)
+	if err != nil {
+		return err
+	}
+
+	start, err := Decode(Encode(latLo, lngLo, codeLen))
+	if err != nil {
+		return err
+	}
+
+	latSteps := int((latHi-start.LatLo)/latSize) + 1
+	lngSteps := int((lngHi-start.LngLo)/lngSize) + 1
+
+	for i := 0; i < latSteps; i++ {
+		lat := start.LatLo + float64(i)*latSize
+		if lat >= latHi {
+			break
+		}
+		for j := 0; j < lngSteps; j++ {
+			lng := start.LngLo + float64(j)*lngSize
+			if lng >= lngHi {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case out <- Encode(lat+latSize/2, normalizeLongitude(lng+lngSize/2), codeLen):
+			}
+		}
+	}
+	return nil
+}
+
+// cellSize returns the height and width, in degrees, of a cell encoded
+// with codeLen digits. Cell size depends only on the code's length, not
+// on its location, so it is derived by round-tripping a code anchored
+// at the origin.
+func cellSize(codeLen int) (latSize, lngSize float64, err error) {
+	area, err := Decode(Encode(0, 0, codeLen))
+	if err != nil {
+		return 0, 0, err
+	}
+	return area.LatHi - area.LatLo, area.LngHi - area.LngLo, nil
+}
+
+func clampLatitude(lat float64) float64 {
+	if lat > 90 {
+		return 90
+	}
+	if lat < -90 {
+		return -90
+	}
+	return lat
+}
+
+func normalizeLongitude(lng float64) float64 {
+	for lng < -180 {
+		lng += 360
+	}
+	for lng >= 180 {
+		lng -= 360
+	}
+	return lng
+}
@@ -0,0 +1,108 @@
+// Copyright 2015 Tamás Gulácsi. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package olc
+
+import (
+	"testing"
+
+	"github.com/tgulacsi/go/loghlp/tsthlp"
+)
+
+// directionMuls gives, for each of the 8 Neighbors slots in compass
+// order (N, NE, E, SE, S, SW, W, NW), the multiple of latSize/lngSize
+// that the neighbor's center is offset from the fixture's own center.
+// This mirrors the offsets table in neighbors.go.
+var directionMuls = [8]struct{ latMul, lngMul float64 }{
+	{1, 0},
+	{1, 1},
+	{0, 1},
+	{-1, 1},
+	{-1, 0},
+	{-1, -1},
+	{0, -1},
+	{1, -1},
+}
+
+// TestNeighbors derives the expected 8 neighbors of each
+// encodingTests.csv fixture by perturbing its latLo/lngLo/latHi/lngHi
+// into the adjoining cells and re-encoding, then checks Neighbors
+// agrees in every direction.
+func TestNeighbors(t *testing.T) {
+	Log.SetHandler(tsthlp.TestHandler(t))
+	for i, elt := range encoding {
+		n := len(stripCode(elt.code))
+		latSize := elt.latHi - elt.latLo
+		lngSize := elt.lngHi - elt.lngLo
+		latCenter := (elt.latLo + elt.latHi) / 2
+		lngCenter := (elt.lngLo + elt.lngHi) / 2
+
+		got, err := Neighbors(elt.code)
+		if err != nil {
+			t.Errorf("%d. %q: %v", i, elt.code, err)
+			continue
+		}
+
+		for dir, mul := range directionMuls {
+			want := Encode(
+				clampLatitude(latCenter+mul.latMul*latSize),
+				normalizeLongitude(lngCenter+mul.lngMul*lngSize),
+				n)
+			if got[dir] != want {
+				t.Errorf("%d. %q direction %d got %q, awaited %q", i, elt.code, dir, got[dir], want)
+			}
+		}
+	}
+}
+
+// TestClampLatitude exercises both the north and south clamping
+// branches, which a polar fixture in encodingTests.csv may not reach.
+func TestClampLatitude(t *testing.T) {
+	for _, tt := range []struct{ lat, want float64 }{
+		{45, 45},
+		{90, 90},
+		{-90, -90},
+		{95, 90},
+		{-95, -90},
+	} {
+		if got := clampLatitude(tt.lat); got != tt.want {
+			t.Errorf("clampLatitude(%v) = %v, want %v", tt.lat, got, tt.want)
+		}
+	}
+}
+
+// TestCellsIn checks that CellsIn recovers each fixture's own code
+// when asked for the cells covering that fixture's exact bounding box.
+func TestCellsIn(t *testing.T) {
+	Log.SetHandler(tsthlp.TestHandler(t))
+	for i, elt := range encoding {
+		n := len(stripCode(elt.code))
+		codes, err := CellsIn(elt.latLo, elt.lngLo, elt.latHi, elt.lngHi, n)
+		if err != nil {
+			t.Errorf("%d. %q: %v", i, elt.code, err)
+			continue
+		}
+		found := false
+		for _, code := range codes {
+			if code == elt.code {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("%d. %q not present in CellsIn(%v,%v,%v,%v,%d)=%v",
+				i, elt.code, elt.latLo, elt.lngLo, elt.latHi, elt.lngHi, n, codes)
+		}
+	}
+}